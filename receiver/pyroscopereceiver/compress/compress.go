@@ -0,0 +1,128 @@
+// Package compress implements a codec registry used to decompress ingest
+// request bodies, negotiating the codec from a client-supplied
+// Content-Encoding rather than assuming gzip.
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Codec names a registered decompression algorithm, matching the value
+// expected in a Content-Encoding header.
+type Codec string
+
+const (
+	Gzip     Codec = "gzip"
+	Zstd     Codec = "zstd"
+	Snappy   Codec = "snappy"
+	Deflate  Codec = "deflate"
+	Identity Codec = "identity"
+)
+
+// ErrUnsupportedEncoding is returned when a client requests a codec that is
+// either not registered or excluded by the configured allow-list.
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// decoderFactory wraps r in a decoder for the codec it is registered under.
+type decoderFactory func(r io.Reader) (io.ReadCloser, error)
+
+var registry = map[Codec]decoderFactory{
+	Gzip: func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	Zstd: func(r io.Reader) (io.ReadCloser, error) {
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	},
+	Snappy: func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(snappy.NewReader(r)), nil
+	},
+	Deflate: func(r io.Reader) (io.ReadCloser, error) {
+		return flate.NewReader(r), nil
+	},
+	Identity: func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(r), nil
+	},
+}
+
+// ParseCodec maps a Content-Encoding header value onto a registered Codec.
+func ParseCodec(encoding string) (Codec, error) {
+	c := Codec(encoding)
+	if _, ok := registry[c]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+	return c, nil
+}
+
+// Decompressor decompresses a request body using a codec negotiated from
+// the client's Content-Encoding, enforcing a hard cap on the decompressed
+// size so a zip-bomb payload can't OOM the collector.
+type Decompressor struct {
+	maxUncompressedSize int64
+	defaultCodec        Codec
+	supportedEncodings  map[Codec]struct{}
+}
+
+// NewDecompressor constructs a Decompressor. maxUncompressedSize bounds the
+// decompressed output. defaultCodec is used when a request carries no
+// Content-Encoding. supportedEncodings restricts the accepted codecs; a nil
+// or empty slice accepts every codec registered above.
+func NewDecompressor(maxUncompressedSize int64, defaultCodec Codec, supportedEncodings []string) *Decompressor {
+	d := &Decompressor{maxUncompressedSize: maxUncompressedSize, defaultCodec: defaultCodec}
+	if len(supportedEncodings) > 0 {
+		d.supportedEncodings = make(map[Codec]struct{}, len(supportedEncodings))
+		for _, e := range supportedEncodings {
+			d.supportedEncodings[Codec(e)] = struct{}{}
+		}
+	}
+	return d
+}
+
+// Decompress decodes r using the codec named by encoding (falling back to
+// the configured default when encoding is empty) into buf. It returns
+// ErrUnsupportedEncoding if the codec is unknown or outside the configured
+// allow-list, and an error once more than maxUncompressedSize bytes have
+// been produced.
+func (d *Decompressor) Decompress(r io.Reader, encoding string, buf *bytes.Buffer) error {
+	codec := d.defaultCodec
+	if encoding != "" {
+		c, err := ParseCodec(encoding)
+		if err != nil {
+			return err
+		}
+		codec = c
+	}
+	if d.supportedEncodings != nil {
+		if _, ok := d.supportedEncodings[codec]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnsupportedEncoding, codec)
+		}
+	}
+
+	newDecoder := registry[codec]
+	dr, err := newDecoder(r)
+	if err != nil {
+		return fmt.Errorf("failed to open %s decoder: %w", codec, err)
+	}
+	defer dr.Close()
+
+	limited := &io.LimitedReader{R: dr, N: d.maxUncompressedSize + 1}
+	n, err := buf.ReadFrom(limited)
+	if err != nil {
+		return fmt.Errorf("failed to decompress: %w", err)
+	}
+	if n > d.maxUncompressedSize {
+		return fmt.Errorf("decompressed payload exceeds max size of %d bytes", d.maxUncompressedSize)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+func compressWith(t *testing.T, codec Codec, payload []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	switch codec {
+	case Gzip:
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			t.Fatalf("failed to write gzip payload: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to close gzip writer: %s", err)
+		}
+	case Zstd:
+		w, err := zstd.NewWriter(&buf)
+		if err != nil {
+			t.Fatalf("failed to create zstd writer: %s", err)
+		}
+		if _, err := w.Write(payload); err != nil {
+			t.Fatalf("failed to write zstd payload: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to close zstd writer: %s", err)
+		}
+	case Snappy:
+		w := snappy.NewBufferedWriter(&buf)
+		if _, err := w.Write(payload); err != nil {
+			t.Fatalf("failed to write snappy payload: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to close snappy writer: %s", err)
+		}
+	case Deflate:
+		w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		if err != nil {
+			t.Fatalf("failed to create deflate writer: %s", err)
+		}
+		if _, err := w.Write(payload); err != nil {
+			t.Fatalf("failed to write deflate payload: %s", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("failed to close deflate writer: %s", err)
+		}
+	case Identity:
+		buf.Write(payload)
+	default:
+		t.Fatalf("unsupported codec in test: %s", codec)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompressRoundTrip(t *testing.T) {
+	payload := []byte("the quick brown fox jumps over the lazy dog")
+
+	for _, codec := range []Codec{Gzip, Zstd, Snappy, Deflate, Identity} {
+		codec := codec
+		t.Run(string(codec), func(t *testing.T) {
+			compressed := compressWith(t, codec, payload)
+
+			d := NewDecompressor(int64(len(payload)), Identity, nil)
+			var out bytes.Buffer
+			if err := d.Decompress(bytes.NewReader(compressed), string(codec), &out); err != nil {
+				t.Fatalf("Decompress() returned error: %s", err)
+			}
+			if !bytes.Equal(out.Bytes(), payload) {
+				t.Fatalf("Decompress() = %q, want %q", out.Bytes(), payload)
+			}
+		})
+	}
+}
+
+func TestDecompressDefaultCodec(t *testing.T) {
+	payload := []byte("default codec payload")
+	compressed := compressWith(t, Gzip, payload)
+
+	d := NewDecompressor(int64(len(payload)), Gzip, nil)
+	var out bytes.Buffer
+	if err := d.Decompress(bytes.NewReader(compressed), "", &out); err != nil {
+		t.Fatalf("Decompress() returned error: %s", err)
+	}
+	if !bytes.Equal(out.Bytes(), payload) {
+		t.Fatalf("Decompress() = %q, want %q", out.Bytes(), payload)
+	}
+}
+
+func TestDecompressUnsupportedEncoding(t *testing.T) {
+	d := NewDecompressor(1024, Identity, []string{string(Gzip)})
+
+	var out bytes.Buffer
+	err := d.Decompress(bytes.NewReader([]byte("payload")), string(Snappy), &out)
+	if !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Fatalf("Decompress() error = %v, want ErrUnsupportedEncoding", err)
+	}
+}
+
+func TestDecompressUnknownEncoding(t *testing.T) {
+	d := NewDecompressor(1024, Identity, nil)
+
+	var out bytes.Buffer
+	err := d.Decompress(bytes.NewReader([]byte("payload")), "brotli", &out)
+	if !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Fatalf("Decompress() error = %v, want ErrUnsupportedEncoding", err)
+	}
+}
+
+// TestDecompressSizeCap guards against a zip-bomb payload: a small
+// compressed input that expands far past the configured max size must be
+// rejected rather than fully buffered in memory.
+func TestDecompressSizeCap(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 1<<20) // 1MiB of highly compressible data
+	compressed := compressWith(t, Gzip, payload)
+
+	d := NewDecompressor(1024, Identity, nil)
+	var out bytes.Buffer
+	err := d.Decompress(bytes.NewReader(compressed), string(Gzip), &out)
+	if err == nil {
+		t.Fatal("Decompress() returned no error for a payload exceeding the size cap")
+	}
+}
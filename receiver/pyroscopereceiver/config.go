@@ -0,0 +1,76 @@
+package pyroscopereceiver
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// Config defines configuration for the pyroscope receiver.
+type Config struct {
+	Protocols Protocols `mapstructure:"protocols"`
+
+	// Timeout bounds how long the receiver will work on a single ingest
+	// request before responding with a 408.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// RetrySettings configures the exponential backoff retry policy applied
+	// when handing parsed profiles to the next consumer in the pipeline.
+	RetrySettings exporterhelper.RetrySettings `mapstructure:"retry_on_failure"`
+
+	// RateLimit enforces a per-remote-IP token bucket in front of the http
+	// ingest handler.
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+}
+
+// Protocols holds the set of ingest protocols the receiver exposes. Http is
+// always enabled; Grpc is optional.
+type Protocols struct {
+	Http HTTPConfig  `mapstructure:"http"`
+	Grpc *GRPCConfig `mapstructure:"grpc"`
+}
+
+// HTTPConfig configures the /ingest http endpoint.
+type HTTPConfig struct {
+	confighttp.HTTPServerSettings `mapstructure:",squash"`
+
+	// MaxRequestBodySize bounds both the accepted request body and the
+	// decompressed size the receiver will produce from it.
+	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
+
+	// SupportedEncodings restricts which Content-Encoding codecs the
+	// receiver will accept; a client outside this list gets a 415. Empty
+	// means every codec registered with the compress package is accepted.
+	SupportedEncodings []string `mapstructure:"supported_encodings"`
+}
+
+// GRPCConfig configures the streaming gRPC ingest endpoint.
+type GRPCConfig struct {
+	configgrpc.GRPCServerSettings `mapstructure:",squash"`
+}
+
+// RateLimitConfig configures the per-remote-IP token bucket applied to the
+// http ingest handler.
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Rps is the sustained number of requests per second allowed per
+	// remote IP.
+	Rps float64 `mapstructure:"rps"`
+	// Burst is the maximum number of requests a single remote IP may send
+	// in a single instant.
+	Burst int `mapstructure:"burst"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.RateLimit.Enabled && cfg.RateLimit.Rps <= 0 {
+		return fmt.Errorf("rate_limit.rps must be positive when rate_limit is enabled")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package pyroscopereceiver
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+func TestParseNameAndMatchers(t *testing.T) {
+	tests := []struct {
+		name         string
+		selector     string
+		wantName     string
+		wantMatchers []*labels.Matcher
+		wantErr      bool
+	}{
+		{
+			name:     "bare name, no matchers",
+			selector: "app.name",
+			wantName: "app.name",
+		},
+		{
+			name:     "empty matcher list",
+			selector: "app.name{}",
+			wantName: "app.name",
+		},
+		{
+			name:     "whitespace-only matcher list",
+			selector: "app.name{ }",
+			wantName: "app.name",
+		},
+		{
+			name:     "single equality matcher",
+			selector: `app.name{region="us-east-1"}`,
+			wantName: "app.name",
+			wantMatchers: []*labels.Matcher{
+				mustNewMatcher(t, labels.MatchEqual, "region", "us-east-1"),
+			},
+		},
+		{
+			name:     "matcher-only selector, no app name",
+			selector: `{region="us-east-1"}`,
+			wantName: "",
+			wantMatchers: []*labels.Matcher{
+				mustNewMatcher(t, labels.MatchEqual, "region", "us-east-1"),
+			},
+		},
+		{
+			name:     "non-equality and regex matchers",
+			selector: `app.name{region!="us-east-1",host=~"web-.*",az!~"eu-.*"}`,
+			wantName: "app.name",
+			wantMatchers: []*labels.Matcher{
+				mustNewMatcher(t, labels.MatchNotEqual, "region", "us-east-1"),
+				mustNewMatcher(t, labels.MatchRegexp, "host", "web-.*"),
+				mustNewMatcher(t, labels.MatchNotRegexp, "az", "eu-.*"),
+			},
+		},
+		{
+			name:     "escaped quote in value",
+			selector: `app.name{region="us-\"east\"-1"}`,
+			wantName: "app.name",
+			wantMatchers: []*labels.Matcher{
+				mustNewMatcher(t, labels.MatchEqual, "region", `us-"east"-1`),
+			},
+		},
+		{
+			name:     "unicode value",
+			selector: `app.name{region="東京"}`,
+			wantName: "app.name",
+			wantMatchers: []*labels.Matcher{
+				mustNewMatcher(t, labels.MatchEqual, "region", "東京"),
+			},
+		},
+		{
+			name:     "missing closing brace",
+			selector: `app.name{region="us-east-1"`,
+			wantErr:  true,
+		},
+		{
+			name:     "malformed matcher list",
+			selector: `app.name{region=}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, matchers, err := parseNameAndMatchers(tt.selector)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseNameAndMatchers(%q) returned no error, want one", tt.selector)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseNameAndMatchers(%q) returned error: %s", tt.selector, err)
+			}
+			if name != tt.wantName {
+				t.Errorf("parseNameAndMatchers(%q) name = %q, want %q", tt.selector, name, tt.wantName)
+			}
+			if len(matchers) != len(tt.wantMatchers) {
+				t.Fatalf("parseNameAndMatchers(%q) matchers = %v, want %v", tt.selector, matchers, tt.wantMatchers)
+			}
+			for i, m := range matchers {
+				want := tt.wantMatchers[i]
+				if m.Type != want.Type || m.Name != want.Name || m.Value != want.Value {
+					t.Errorf("parseNameAndMatchers(%q) matcher[%d] = %+v, want %+v", tt.selector, i, m, want)
+				}
+			}
+		})
+	}
+}
+
+func mustNewMatcher(t *testing.T, mt labels.MatchType, name, value string) *labels.Matcher {
+	t.Helper()
+	m, err := labels.NewMatcher(mt, name, value)
+	if err != nil {
+		t.Fatalf("failed to build test matcher: %s", err)
+	}
+	return m
+}
@@ -0,0 +1,60 @@
+package pyroscopereceiver
+
+import (
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	otelcolReceiverPyroscopeHttpRequestTotal                 metric.Int64Counter
+	otelcolReceiverPyroscopeHttpRequestThrottled             metric.Int64Counter
+	otelcolReceiverPyroscopeHttpResponseTimeMillis           metric.Int64Histogram
+	otelcolReceiverPyroscopeRequestBodyUncompressedSizeBytes metric.Int64Histogram
+	otelcolReceiverPyroscopeParsedBodyUncompressedSizeBytes  metric.Int64Histogram
+)
+
+// initMetrics creates the instruments the receiver records against, binding
+// each package-level var to meter. It must be called once per receiver
+// instance before any request is handled.
+func initMetrics(meter metric.Meter) error {
+	var err error
+
+	if otelcolReceiverPyroscopeHttpRequestTotal, err = meter.Int64Counter(
+		"otelcol_receiver_pyroscope_http_request_total",
+		metric.WithDescription("Number of http ingest requests handled, labeled by service and error_code."),
+	); err != nil {
+		return err
+	}
+
+	if otelcolReceiverPyroscopeHttpRequestThrottled, err = meter.Int64Counter(
+		"otelcol_receiver_pyroscope_http_request_throttled",
+		metric.WithDescription("Number of http ingest requests rejected by the per-IP rate limiter."),
+	); err != nil {
+		return err
+	}
+
+	if otelcolReceiverPyroscopeHttpResponseTimeMillis, err = meter.Int64Histogram(
+		"otelcol_receiver_pyroscope_http_response_time_millis",
+		metric.WithDescription("Http ingest request handling time in milliseconds, labeled by service and error_code."),
+		metric.WithUnit("ms"),
+	); err != nil {
+		return err
+	}
+
+	if otelcolReceiverPyroscopeRequestBodyUncompressedSizeBytes, err = meter.Int64Histogram(
+		"otelcol_receiver_pyroscope_request_body_uncompressed_size_bytes",
+		metric.WithDescription("Size of the decompressed ingest request body in bytes, labeled by service, type and encoding."),
+		metric.WithUnit("By"),
+	); err != nil {
+		return err
+	}
+
+	if otelcolReceiverPyroscopeParsedBodyUncompressedSizeBytes, err = meter.Int64Histogram(
+		"otelcol_receiver_pyroscope_parsed_body_uncompressed_size_bytes",
+		metric.WithDescription("Size of the parsed profile payload in bytes, labeled by service, type and encoding."),
+		metric.WithUnit("By"),
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
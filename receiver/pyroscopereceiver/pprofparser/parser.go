@@ -0,0 +1,100 @@
+package pprofparser
+
+import (
+	"bytes"
+	"fmt"
+
+	profile_types "github.com/metrico/otel-collector/receiver/pyroscopereceiver/types"
+
+	"github.com/google/pprof/profile"
+)
+
+// SampleTypeConfig mirrors a single entry of Pyroscope's
+// `sample_type_config.json` multipart part, keyed by sample type name.
+//
+// Only Units is applied today: profile_types.ProfileType has no field to
+// carry Aggregation, Cumulative or DisplayName, so they are accepted here
+// (to match the multipart contract and avoid rejecting a well-formed part)
+// but otherwise dropped. Surfacing them requires widening ProfileType,
+// which is shared with jfrparser.
+type SampleTypeConfig struct {
+	Units       string `json:"units"`
+	Aggregation string `json:"aggregation"`
+	Cumulative  bool   `json:"cumulative"`
+	DisplayName string `json:"display-name"`
+}
+
+// PprofParser parses raw pprof-encoded profile.proto payloads, emitting one
+// profile_types.ProfileIR per sample type contained in the profile.
+type PprofParser struct{}
+
+func NewPprofParser() *PprofParser {
+	return &PprofParser{}
+}
+
+// Parse implements the parser interface for a raw pprof body with no
+// accompanying sample type metadata.
+func (p *PprofParser) Parse(buf *bytes.Buffer, md profile_types.Metadata) ([]profile_types.ProfileIR, error) {
+	return p.ParseWithConfig(buf, md, nil)
+}
+
+// ParseWithConfig parses a raw pprof body, applying the units override
+// carried in a Pyroscope `sample_type_config.json` part. The aggregation,
+// cumulative and display-name entries of that part are not applied.
+//
+// md is accepted for parity with the jfr parser but is otherwise unused: a
+// pprof profile.proto payload already carries its own period/frequency in
+// PeriodType, so md.SampleRateHertz (meaningful for jfr's fixed-rate
+// sampling) has nothing to override here.
+func (p *PprofParser) ParseWithConfig(buf *bytes.Buffer, md profile_types.Metadata, cfg map[string]SampleTypeConfig) ([]profile_types.ProfileIR, error) {
+	prof, err := profile.Parse(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pprof profile: %w", err)
+	}
+
+	irs := make([]profile_types.ProfileIR, 0, len(prof.SampleType))
+	for i, st := range prof.SampleType {
+		single, err := selectSampleType(prof, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to isolate sample type %q: %w", st.Type, err)
+		}
+
+		sc := cfg[st.Type]
+		unit := st.Unit
+		if sc.Units != "" {
+			unit = sc.Units
+		}
+
+		var payload bytes.Buffer
+		if err := single.Write(&payload); err != nil {
+			return nil, fmt.Errorf("failed to encode sample type %q: %w", st.Type, err)
+		}
+
+		irs = append(irs, profile_types.ProfileIR{
+			Type: profile_types.ProfileType{
+				Type:       st.Type,
+				SampleType: []string{st.Type},
+				SampleUnit: []string{unit},
+				PeriodType: prof.PeriodType.Type,
+				PeriodUnit: prof.PeriodType.Unit,
+			},
+			Payload:     payload,
+			PayloadType: profile_types.PayloadTypePprof,
+		})
+	}
+	return irs, nil
+}
+
+// selectSampleType returns a copy of prof that retains only the sample type
+// at index i, dropping the rest of the values from every sample.
+func selectSampleType(prof *profile.Profile, i int) (*profile.Profile, error) {
+	cp := prof.Copy()
+	cp.SampleType = []*profile.ValueType{prof.SampleType[i]}
+	for _, s := range cp.Sample {
+		s.Value = []int64{s.Value[i]}
+	}
+	if err := cp.CheckValid(); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
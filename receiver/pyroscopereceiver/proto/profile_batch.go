@@ -0,0 +1,41 @@
+// Package proto holds the Go bindings for profile_batch.proto. They are
+// checked in by hand for now; once protoc-gen-go/protoc-gen-go-grpc are
+// wired into this repo's build, this file and profile_batch_grpc.go should
+// be regenerated from the .proto source rather than edited directly.
+package proto
+
+type Header struct {
+	ServiceName     string
+	Labels          map[string]string
+	StartUnixMilli  uint64
+	EndUnixMilli    uint64
+	SampleRateHertz uint64
+}
+
+type ProfileBatch struct {
+	BatchId         string
+	Header          *Header
+	Format          string
+	Payloads        [][]byte
+	ContentEncoding string
+}
+
+type BatchStatus_Code int32
+
+const (
+	BatchStatus_SUCCESS       BatchStatus_Code = 0
+	BatchStatus_PARSE_ERROR   BatchStatus_Code = 1
+	BatchStatus_OUT_OF_MEMORY BatchStatus_Code = 2
+	BatchStatus_UNAVAILABLE   BatchStatus_Code = 3
+)
+
+type RetryInfo struct {
+	RetryAfterMillis uint64
+}
+
+type BatchStatus struct {
+	BatchId   string
+	Code      BatchStatus_Code
+	RetryInfo *RetryInfo
+	Message   string
+}
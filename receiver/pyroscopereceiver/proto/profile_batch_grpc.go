@@ -0,0 +1,104 @@
+package proto
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ProfileIngestServer is the server API for the ProfileIngest service: a
+// single long-lived bidirectional stream where the client pushes
+// ProfileBatch frames and the server acks each with a BatchStatus.
+type ProfileIngestServer interface {
+	Send(ProfileIngest_SendServer) error
+}
+
+type ProfileIngest_SendServer interface {
+	Send(*BatchStatus) error
+	Recv() (*ProfileBatch, error)
+	grpc.ServerStream
+}
+
+type profileIngestSendServer struct {
+	grpc.ServerStream
+}
+
+func (s *profileIngestSendServer) Send(m *BatchStatus) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *profileIngestSendServer) Recv() (*ProfileBatch, error) {
+	m := new(ProfileBatch)
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ProfileIngest_Send_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ProfileIngestServer).Send(&profileIngestSendServer{stream})
+}
+
+// RegisterProfileIngestServer registers srv with s under the ProfileIngest
+// service descriptor.
+func RegisterProfileIngestServer(s grpc.ServiceRegistrar, srv ProfileIngestServer) {
+	s.RegisterService(&ServiceDesc, srv)
+}
+
+// ServiceDesc is the grpc.ServiceDesc for the ProfileIngest service.
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pyroscopereceiver.proto.ProfileIngest",
+	HandlerType: (*ProfileIngestServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Send",
+			Handler:       _ProfileIngest_Send_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "profile_batch.proto",
+}
+
+// ProfileIngestClient is the client API for the ProfileIngest service.
+type ProfileIngestClient interface {
+	Send(ctx context.Context, opts ...grpc.CallOption) (ProfileIngest_SendClient, error)
+}
+
+type ProfileIngest_SendClient interface {
+	Send(*ProfileBatch) error
+	Recv() (*BatchStatus, error)
+	grpc.ClientStream
+}
+
+type profileIngestClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProfileIngestClient(cc grpc.ClientConnInterface) ProfileIngestClient {
+	return &profileIngestClient{cc}
+}
+
+func (c *profileIngestClient) Send(ctx context.Context, opts ...grpc.CallOption) (ProfileIngest_SendClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ServiceDesc.Streams[0], "/pyroscopereceiver.proto.ProfileIngest/Send", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &profileIngestSendClient{stream}, nil
+}
+
+type profileIngestSendClient struct {
+	grpc.ClientStream
+}
+
+func (c *profileIngestSendClient) Send(m *ProfileBatch) error {
+	return c.ClientStream.SendMsg(m)
+}
+
+func (c *profileIngestSendClient) Recv() (*BatchStatus, error) {
+	m := new(BatchStatus)
+	if err := c.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
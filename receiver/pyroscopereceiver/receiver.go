@@ -3,8 +3,11 @@ package pyroscopereceiver
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"mime/multipart"
 	"net"
 	"net/http"
@@ -12,20 +15,41 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/metrico/otel-collector/receiver/pyroscopereceiver/compress"
 	"github.com/metrico/otel-collector/receiver/pyroscopereceiver/jfrparser"
+	"github.com/metrico/otel-collector/receiver/pyroscopereceiver/pprofparser"
+	pyroscopeproto "github.com/metrico/otel-collector/receiver/pyroscopereceiver/proto"
 	profile_types "github.com/metrico/otel-collector/receiver/pyroscopereceiver/types"
 	"github.com/prometheus/prometheus/model/labels"
+	promqlparser "github.com/prometheus/prometheus/promql/parser"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+)
+
+// defaultGrpcMaxConcurrentBatches bounds the number of ProfileBatch frames
+// being decompressed/parsed/consumed concurrently. A single grpcHandler
+// serves every stream, so the bound is shared across all streams.
+const defaultGrpcMaxConcurrentBatches = 32
+
+// rateLimiterIdleTTL and rateLimiterSweepInterval bound the size of the
+// per-IP rate limiter map: an entry idle longer than the TTL is evicted by
+// a sweep running at this interval.
+const (
+	rateLimiterIdleTTL       = 10 * time.Minute
+	rateLimiterSweepInterval = time.Minute
 )
 
 const (
@@ -34,6 +58,10 @@ const (
 	formatJfr   = "jfr"
 	formatPprof = "pprof"
 
+	partProfile          = "profile"
+	partSampleTypeConfig = "sample_type_config.json"
+	partPreviousProfile  = "previous_profile"
+
 	errorCodeError   = "1"
 	errorCodeSuccess = ""
 
@@ -55,9 +83,26 @@ type pyroscopeReceiver struct {
 	httpMux      *http.ServeMux
 	decompressor *compress.Decompressor
 	httpServer   *http.Server
+	grpcServer   *grpc.Server
 	shutdownWg   sync.WaitGroup
 
 	uncompressedBufPool *sync.Pool
+
+	// rateLimiters holds one *rateLimiterEntry per remote IP, lazily
+	// created. sweepRateLimiters evicts entries that have gone idle for
+	// longer than rateLimiterIdleTTL so a stream of distinct IPs can't grow
+	// this map without bound.
+	rateLimiters      sync.Map
+	rateLimiterStopCh chan struct{}
+}
+
+// rateLimiterEntry pairs a per-IP token bucket with the last time it was
+// used, so sweepRateLimiters can evict entries nobody has hit recently.
+// lastSeen is accessed with the sync/atomic package since it's read and
+// written from both request-handling goroutines and the sweep goroutine.
+type rateLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen int64 // unix nano
 }
 
 type parser interface {
@@ -66,10 +111,10 @@ type parser interface {
 }
 
 type params struct {
-	start  uint64
-	end    uint64
-	name   string
-	labels labels.Labels
+	start    uint64
+	end      uint64
+	name     string
+	matchers []*labels.Matcher
 }
 
 func newPyroscopeReceiver(cfg *Config, consumer consumer.Logs, set *receiver.CreateSettings) (*pyroscopeReceiver, error) {
@@ -81,7 +126,7 @@ func newPyroscopeReceiver(cfg *Config, consumer consumer.Logs, set *receiver.Cre
 		next:                consumer,
 		uncompressedBufPool: &sync.Pool{},
 	}
-	recv.decompressor = compress.NewDecompressor(recv.cfg.Protocols.Http.MaxRequestBodySize)
+	recv.decompressor = compress.NewDecompressor(recv.cfg.Protocols.Http.MaxRequestBodySize, compress.Gzip, recv.cfg.Protocols.Http.SupportedEncodings)
 	recv.httpMux = http.NewServeMux()
 	recv.httpMux.HandleFunc(ingestPath, func(resp http.ResponseWriter, req *http.Request) {
 		recv.httpHandlerIngest(resp, req)
@@ -90,14 +135,25 @@ func newPyroscopeReceiver(cfg *Config, consumer consumer.Logs, set *receiver.Cre
 		recv.logger.Error(fmt.Sprintf("failed to init metrics: %s", err.Error()))
 		return recv, err
 	}
+
+	if recv.cfg.Protocols.Grpc != nil {
+		recv.grpcServer = grpc.NewServer()
+		pyroscopeproto.RegisterProfileIngestServer(recv.grpcServer, newGrpcHandler(recv, defaultGrpcMaxConcurrentBatches))
+	}
 	return recv, nil
 }
 
-// TODO: rate limit clients
 func (recv *pyroscopeReceiver) httpHandlerIngest(resp http.ResponseWriter, req *http.Request) {
 	ctx, cancel := context.WithTimeout(contextWithStart(req.Context(), time.Now().UnixMilli()), recv.cfg.Timeout)
 	defer cancel()
 
+	if !recv.allowRequest(req) {
+		otelcolReceiverPyroscopeHttpRequestThrottled.Add(ctx, 1, metric.WithAttributeSet(*newOtelcolAttrSetHttp("", errorCodeError)))
+		resp.Header().Set("Retry-After", "1")
+		writeResponse(resp, "text/plain", http.StatusTooManyRequests, []byte("rate limit exceeded"))
+		return
+	}
+
 	// all compute should be bounded by timeout, so dont add compute here
 
 	select {
@@ -108,6 +164,48 @@ func (recv *pyroscopeReceiver) httpHandlerIngest(resp http.ResponseWriter, req *
 	}
 }
 
+// allowRequest enforces a per-remote-IP token bucket ahead of any
+// decompression/parsing work, so an abusive client cannot burn CPU before
+// being turned away.
+func (recv *pyroscopeReceiver) allowRequest(req *http.Request) bool {
+	if !recv.cfg.RateLimit.Enabled {
+		return true
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	now := time.Now().UnixNano()
+	if v, ok := recv.rateLimiters.Load(host); ok {
+		e := v.(*rateLimiterEntry)
+		atomic.StoreInt64(&e.lastSeen, now)
+		return e.limiter.Allow()
+	}
+
+	// only construct a new limiter on a cache miss; LoadOrStore would
+	// otherwise allocate one on every call, even when host already has one.
+	e := &rateLimiterEntry{
+		limiter:  rate.NewLimiter(rate.Limit(recv.cfg.RateLimit.Rps), recv.cfg.RateLimit.Burst),
+		lastSeen: now,
+	}
+	v, _ := recv.rateLimiters.LoadOrStore(host, e)
+	return v.(*rateLimiterEntry).limiter.Allow()
+}
+
+// sweepRateLimiters evicts rate limiter entries that have gone idle for
+// longer than rateLimiterIdleTTL, keeping the per-IP map bounded in the
+// face of a long tail of distinct remote IPs.
+func (recv *pyroscopeReceiver) sweepRateLimiters() {
+	cutoff := time.Now().Add(-rateLimiterIdleTTL).UnixNano()
+	recv.rateLimiters.Range(func(k, v interface{}) bool {
+		if atomic.LoadInt64(&v.(*rateLimiterEntry).lastSeen) < cutoff {
+			recv.rateLimiters.Delete(k)
+		}
+		return true
+	})
+}
+
 func startTimeFromContext(ctx context.Context) int64 {
 	return ctx.Value(keyStart).(int64)
 }
@@ -125,7 +223,7 @@ func (recv *pyroscopeReceiver) handle(ctx context.Context, resp http.ResponseWri
 		qs := req.URL.Query()
 		pm, err := readParams(&qs)
 		if err != nil {
-			recv.handleError(ctx, resp, "text/plain", http.StatusBadRequest, "bad url query", "", errorCodeError)
+			recv.handleError(ctx, resp, "text/plain", http.StatusBadRequest, err.Error(), "", errorCodeError)
 			return
 		}
 
@@ -136,7 +234,12 @@ func (recv *pyroscopeReceiver) handle(ctx context.Context, resp http.ResponseWri
 
 		pl, err := recv.readProfiles(ctx, req, pm)
 		if err != nil {
-			recv.handleError(ctx, resp, "text/plain", http.StatusBadRequest, err.Error(), pm.name, errorCodeError)
+			var uee *unsupportedEncodingError
+			if errors.As(err, &uee) {
+				recv.handleError(ctx, resp, "text/plain", http.StatusUnsupportedMediaType, err.Error(), pm.name, errorCodeError)
+			} else {
+				recv.handleError(ctx, resp, "text/plain", http.StatusBadRequest, err.Error(), pm.name, errorCodeError)
+			}
 			return
 		}
 		// if no profiles have been parsed, dont error but return
@@ -145,11 +248,15 @@ func (recv *pyroscopeReceiver) handle(ctx context.Context, resp http.ResponseWri
 			return
 		}
 
-		// delegate to next consumer in the pipeline
-		// TODO: support memorylimiter processor, apply retry policy on "oom" event, depends on https://github.com/open-telemetry/opentelemetry-collector/issues/9196
-		err = recv.next.ConsumeLogs(ctx, pl)
+		// delegate to next consumer in the pipeline, retrying transient
+		// failures with backoff
+		err = recv.consumeWithRetry(ctx, pl)
 		if err != nil {
-			recv.handleError(ctx, resp, "text/plain", http.StatusInternalServerError, err.Error(), pm.name, errorCodeError)
+			if errors.Is(err, context.DeadlineExceeded) {
+				recv.handleError(ctx, resp, "text/plain", http.StatusRequestTimeout, fmt.Sprintf("receiver timeout elapsed: %s", recv.cfg.Timeout), pm.name, errorCodeError)
+			} else {
+				recv.handleError(ctx, resp, "text/plain", http.StatusInternalServerError, err.Error(), pm.name, errorCodeError)
+			}
 			return
 		}
 
@@ -160,6 +267,47 @@ func (recv *pyroscopeReceiver) handle(ctx context.Context, resp http.ResponseWri
 	return c
 }
 
+// consumeWithRetry delegates to next.ConsumeLogs, retrying transient
+// failures with exponential backoff until RetrySettings.MaxElapsedTime or
+// ctx's own deadline elapses, whichever comes first. consumererror.Permanent
+// errors are never retried.
+func (recv *pyroscopeReceiver) consumeWithRetry(ctx context.Context, pl plog.Logs) error {
+	if !recv.cfg.RetrySettings.Enabled {
+		return recv.next.ConsumeLogs(ctx, pl)
+	}
+
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = recv.cfg.RetrySettings.InitialInterval
+	expBackoff.MaxInterval = recv.cfg.RetrySettings.MaxInterval
+	expBackoff.MaxElapsedTime = recv.cfg.RetrySettings.MaxElapsedTime
+	expBackoff.Multiplier = recv.cfg.RetrySettings.Multiplier
+	expBackoff.RandomizationFactor = recv.cfg.RetrySettings.RandomizationFactor
+	expBackoff.Reset()
+
+	for {
+		err := recv.next.ConsumeLogs(ctx, pl)
+		if err == nil {
+			return nil
+		}
+		if consumererror.IsPermanent(err) {
+			return err
+		}
+
+		next := expBackoff.NextBackOff()
+		if next == backoff.Stop {
+			return err
+		}
+
+		t := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
 func (recv *pyroscopeReceiver) handleError(ctx context.Context, resp http.ResponseWriter, contentType string, statusCode int, msg string, service string, errorCode string) {
 	otelcolReceiverPyroscopeHttpRequestTotal.Add(ctx, 1, metric.WithAttributeSet(*newOtelcolAttrSetHttp(service, errorCode)))
 	otelcolReceiverPyroscopeHttpResponseTimeMillis.Record(ctx, time.Now().Unix()-startTimeFromContext(ctx), metric.WithAttributeSet(*newOtelcolAttrSetHttp(service, errorCode)))
@@ -187,26 +335,12 @@ func readParams(qs *url.Values) (params, error) {
 	if tmp, ok = qsv["name"]; !ok {
 		return p, fmt.Errorf("required labels are missing")
 	}
-	i := strings.Index(tmp[0], "{")
-	length := len(tmp[0])
-	if i < 0 {
-		i = length
-	} else { // optional labels
-		// TODO: improve this stupid {k=v(,k=v)*} compiler, checkout pyroscope's implementation
-		promqllike := tmp[0][i+1 : length-1] // stripe {}
-		if len(promqllike) > 0 {
-			words := strings.FieldsFunc(promqllike, func(r rune) bool { return r == '=' || r == ',' })
-			sz := len(words)
-			if sz == 0 || sz%2 != 0 {
-				return p, fmt.Errorf("failed to compile labels")
-			}
-			for j := 0; j < len(words); j += 2 {
-				p.labels = append(p.labels, labels.Label{Name: words[j], Value: words[j+1]})
-			}
-		}
+	name, matchers, err := parseNameAndMatchers(tmp[0])
+	if err != nil {
+		return p, err
 	}
-	// required app name
-	p.name = tmp[0][:i]
+	p.name = name
+	p.matchers = matchers
 
 	if tmp, ok = qsv["until"]; !ok {
 		return p, fmt.Errorf("required end time is missing")
@@ -219,6 +353,31 @@ func readParams(qs *url.Values) (params, error) {
 	return p, nil
 }
 
+// parseNameAndMatchers splits Pyroscope's `app.name{k1="v1",k2=~"re",k3!="v3"}`
+// selector into the bare app name and its label matchers. The matcher
+// grammar (quoting, escapes, !=, =~, !~) is delegated to PromQL's own
+// vector selector parser rather than hand-rolled, since app names may
+// contain characters (e.g. '.') that aren't valid PromQL metric name
+// characters, so the name is stripped off first and only the `{...}` part
+// is parsed as a bare label matcher list.
+func parseNameAndMatchers(selector string) (string, []*labels.Matcher, error) {
+	i := strings.IndexByte(selector, '{')
+	if i < 0 {
+		return selector, nil, nil
+	}
+	if selector[len(selector)-1] != '}' {
+		return "", nil, fmt.Errorf("label matchers must end with '}'")
+	}
+	if strings.TrimSpace(selector[i+1:len(selector)-1]) == "" {
+		return selector[:i], nil, nil
+	}
+	matchers, err := promqlparser.ParseMetricSelector(selector[i:])
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to compile label matchers: %w", err)
+	}
+	return selector[:i], matchers, nil
+}
+
 func newOtelcolAttrSetHttp(service string, errorCode string) *attribute.Set {
 	s := attribute.NewSet(attribute.KeyValue{Key: keyService, Value: attribute.StringValue(service)}, attribute.KeyValue{Key: "error_code", Value: attribute.StringValue(errorCode)})
 	return &s
@@ -238,39 +397,59 @@ func releaseBuf(p *sync.Pool, buf *bytes.Buffer) {
 	p.Put(buf)
 }
 
+// unsupportedEncodingError marks a decompression failure that should
+// surface as 415 Unsupported Media Type rather than the generic 400 used
+// for other readProfiles failures.
+type unsupportedEncodingError struct{ err error }
+
+func (e *unsupportedEncodingError) Error() string { return e.err.Error() }
+func (e *unsupportedEncodingError) Unwrap() error  { return e.err }
+
 func (recv *pyroscopeReceiver) readProfiles(ctx context.Context, req *http.Request, pm params) (plog.Logs, error) {
 	var (
-		tmp []string
-		ok  bool
-		pa  parser
+		tmp    []string
+		ok     bool
+		pa     parser
+		format string
 	)
 	logs := plog.NewLogs()
 
 	qs := req.URL.Query()
-	if tmp, ok = qs["format"]; ok && tmp[0] == "jfr" {
+	if tmp, ok = qs["format"]; !ok {
+		return logs, fmt.Errorf("unsupported format, supported: [%s, %s]", formatJfr, formatPprof)
+	}
+	format = tmp[0]
+	switch format {
+	case formatJfr:
 		pa = jfrparser.NewJfrPprofParser()
-	} else {
-		return logs, fmt.Errorf("unsupported format, supported: [jfr]")
+	case formatPprof:
+		pa = pprofparser.NewPprofParser()
+	default:
+		return logs, fmt.Errorf("unsupported format, supported: [%s, %s]", formatJfr, formatPprof)
 	}
 
-	// support only multipart/form-data
-	f, err := recv.openMultipartJfr(req)
+	body, encoding, stConfig, err := recv.openMultipartRequest(req, format)
 	if err != nil {
 		return logs, err
 	}
-	defer f.Close()
+	if c, ok := body.(io.Closer); ok {
+		defer c.Close()
+	}
 
 	buf := acquireBuf(recv.uncompressedBufPool)
 	defer func() {
 		releaseBuf(recv.uncompressedBufPool, buf)
 	}()
 
-	err = recv.decompressor.Decompress(f, compress.Gzip, buf)
+	err = recv.decompressor.Decompress(body, encoding, buf)
 	if err != nil {
+		if errors.Is(err, compress.ErrUnsupportedEncoding) {
+			return logs, &unsupportedEncodingError{err: err}
+		}
 		return logs, fmt.Errorf("failed to decompress body: %w", err)
 	}
 	// TODO: try measure compressed size
-	otelcolReceiverPyroscopeRequestBodyUncompressedSizeBytes.Record(ctx, int64(buf.Len()), metric.WithAttributeSet(*newOtelcolAttrSetPayloadSizeBytes(pm.name, formatJfr, "")))
+	otelcolReceiverPyroscopeRequestBodyUncompressedSizeBytes.Record(ctx, int64(buf.Len()), metric.WithAttributeSet(*newOtelcolAttrSetPayloadSizeBytes(pm.name, format, "")))
 	resetHeaders(req)
 
 	md := profile_types.Metadata{SampleRateHertz: 0}
@@ -283,9 +462,14 @@ func (recv *pyroscopeReceiver) readProfiles(ctx context.Context, req *http.Reque
 		md.SampleRateHertz = hz
 	}
 
-	ps, err := pa.Parse(buf, md)
+	var ps []profile_types.ProfileIR
+	if pp, isPprof := pa.(*pprofparser.PprofParser); isPprof {
+		ps, err = pp.ParseWithConfig(buf, md, stConfig)
+	} else {
+		ps, err = pa.Parse(buf, md)
+	}
 	if err != nil {
-		return logs, fmt.Errorf("failed to parse pprof: %w", err)
+		return logs, fmt.Errorf("failed to parse %s: %w", format, err)
 	}
 
 	sz := 0
@@ -297,9 +481,7 @@ func (recv *pyroscopeReceiver) readProfiles(ctx context.Context, req *http.Reque
 		m.PutStr("duration_ns", fmt.Sprint(ns(pm.end-pm.start)))
 		m.PutStr("service_name", pm.name)
 		tm := m.PutEmptyMap("tags")
-		for _, l := range pm.labels {
-			tm.PutStr(l.Name, l.Value)
-		}
+		putMatcherTags(tm, pm.matchers)
 		err = setAttrsFromProfile(pr, m)
 		if err != nil {
 			return logs, fmt.Errorf("failed to parse sample types: %v", err)
@@ -308,7 +490,7 @@ func (recv *pyroscopeReceiver) readProfiles(ctx context.Context, req *http.Reque
 		sz += pr.Payload.Len()
 	}
 	// sz may be 0 and it will be recorded
-	otelcolReceiverPyroscopeParsedBodyUncompressedSizeBytes.Record(ctx, int64(sz), metric.WithAttributeSet(*newOtelcolAttrSetPayloadSizeBytes(pm.name, formatPprof, "")))
+	otelcolReceiverPyroscopeParsedBodyUncompressedSizeBytes.Record(ctx, int64(sz), metric.WithAttributeSet(*newOtelcolAttrSetPayloadSizeBytes(pm.name, format, "")))
 	return logs, nil
 }
 
@@ -321,9 +503,28 @@ func newOtelcolAttrSetPayloadSizeBytes(service string, typ string, encoding stri
 	return &s
 }
 
-func (recv *pyroscopeReceiver) openMultipartJfr(req *http.Request) (multipart.File, error) {
+// openMultipartRequest returns the decompressable profile body for the given
+// ingest format, along with any pprof sample type config carried alongside
+// it. jfr is always a multipart/form-data upload; pprof accepts either
+// Pyroscope's pprof+sample_type_config multipart form or a raw
+// protobuf-encoded profile.proto body. It also returns the Content-Encoding
+// declared on the body, if any, so the caller can negotiate a decompression
+// codec instead of assuming gzip.
+func (recv *pyroscopeReceiver) openMultipartRequest(req *http.Request, format string) (io.Reader, string, map[string]pprofparser.SampleTypeConfig, error) {
+	switch format {
+	case formatJfr:
+		f, encoding, err := recv.openJfrPart(req)
+		return f, encoding, nil, err
+	case formatPprof:
+		return recv.openPprofBody(req)
+	default:
+		return nil, "", nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func (recv *pyroscopeReceiver) openJfrPart(req *http.Request) (multipart.File, string, error) {
 	if err := req.ParseMultipartForm(recv.cfg.Protocols.Http.MaxRequestBodySize); err != nil {
-		return nil, fmt.Errorf("failed to parse multipart request: %w", err)
+		return nil, "", fmt.Errorf("failed to parse multipart request: %w", err)
 	}
 	mf := req.MultipartForm
 	defer func() {
@@ -332,17 +533,67 @@ func (recv *pyroscopeReceiver) openMultipartJfr(req *http.Request) (multipart.Fi
 
 	part, ok := mf.File[formatJfr]
 	if !ok {
-		return nil, fmt.Errorf("required jfr part is missing")
+		return nil, "", fmt.Errorf("required jfr part is missing")
 	}
 	fh := part[0]
 	if fh.Filename != formatJfr {
-		return nil, fmt.Errorf("jfr filename is not '%s'", formatJfr)
+		return nil, "", fmt.Errorf("jfr filename is not '%s'", formatJfr)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open jfr file")
+	}
+	return f, fh.Header.Get("Content-Encoding"), nil
+}
+
+// openPprofBody accepts either a raw profile.proto body or Pyroscope's
+// pprof+sample_type_config multipart form (parts "profile", optional
+// "sample_type_config.json" and "previous_profile").
+func (recv *pyroscopeReceiver) openPprofBody(req *http.Request) (io.Reader, string, map[string]pprofparser.SampleTypeConfig, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		// raw protobuf-encoded profile.proto body
+		return req.Body, req.Header.Get("Content-Encoding"), nil, nil
+	}
+
+	if err := req.ParseMultipartForm(recv.cfg.Protocols.Http.MaxRequestBodySize); err != nil {
+		return nil, "", nil, fmt.Errorf("failed to parse multipart request: %w", err)
+	}
+	mf := req.MultipartForm
+	defer func() {
+		_ = mf.RemoveAll()
+	}()
+
+	part, ok := mf.File[partProfile]
+	if !ok {
+		return nil, "", nil, fmt.Errorf("required profile part is missing")
 	}
+	fh := part[0]
 	f, err := fh.Open()
 	if err != nil {
-		return nil, fmt.Errorf("failed to open jfr file")
+		return nil, "", nil, fmt.Errorf("failed to open profile part: %w", err)
 	}
-	return f, nil
+
+	var stConfig map[string]pprofparser.SampleTypeConfig
+	if cfgPart, ok := mf.File[partSampleTypeConfig]; ok {
+		cf, err := cfgPart[0].Open()
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to open sample_type_config part: %w", err)
+		}
+		defer cf.Close()
+		if err := json.NewDecoder(cf).Decode(&stConfig); err != nil {
+			return nil, "", nil, fmt.Errorf("failed to parse sample_type_config: %w", err)
+		}
+	}
+	// previous_profile is accepted for Pyroscope client compatibility, but
+	// delta profile computation is not yet supported so it is ignored.
+	if prevPart, ok := mf.File[partPreviousProfile]; ok {
+		pf, err := prevPart[0].Open()
+		if err == nil {
+			pf.Close()
+		}
+	}
+	return f, fh.Header.Get("Content-Encoding"), stConfig, nil
 }
 
 func resetHeaders(req *http.Request) {
@@ -362,6 +613,22 @@ func stringToAnyArray(s []string) []any {
 	return res
 }
 
+// putMatcherTags writes matchers into tm. Equality matchers (the common
+// case) are stored as plain strings to stay compatible with consumers that
+// expect a flat tag map; non-equality matchers (!=, =~, !~) carry their
+// operator type alongside the value since a bare string can't express them.
+func putMatcherTags(tm pcommon.Map, matchers []*labels.Matcher) {
+	for _, lm := range matchers {
+		if lm.Type == labels.MatchEqual {
+			tm.PutStr(lm.Name, lm.Value)
+			continue
+		}
+		sm := tm.PutEmptyMap(lm.Name)
+		sm.PutStr("type", lm.Type.String())
+		sm.PutStr("value", lm.Value)
+	}
+}
+
 func setAttrsFromProfile(prof profile_types.ProfileIR, m pcommon.Map) error {
 	m.PutStr("type", prof.Type.Type)
 	s := m.PutEmptySlice("sample_types")
@@ -403,6 +670,40 @@ func (recv *pyroscopeReceiver) Start(_ context.Context, host component.Host) err
 			host.ReportFatalError(err)
 		}
 	}()
+
+	if recv.grpcServer != nil {
+		var gl net.Listener
+		if gl, err = recv.cfg.Protocols.Grpc.ToListener(); err != nil {
+			return fmt.Errorf("failed to create grpc tcp listener: %w", err)
+		}
+		recv.logger.Info("grpc server listening on", zap.String("endpoint", recv.cfg.Protocols.Grpc.Endpoint))
+
+		recv.shutdownWg.Add(1)
+		go func() {
+			defer recv.shutdownWg.Done()
+			if err := recv.grpcServer.Serve(gl); err != nil {
+				host.ReportFatalError(err)
+			}
+		}()
+	}
+
+	if recv.cfg.RateLimit.Enabled {
+		recv.rateLimiterStopCh = make(chan struct{})
+		recv.shutdownWg.Add(1)
+		go func() {
+			defer recv.shutdownWg.Done()
+			ticker := time.NewTicker(rateLimiterSweepInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					recv.sweepRateLimiters()
+				case <-recv.rateLimiterStopCh:
+					return
+				}
+			}
+		}()
+	}
 	return nil
 }
 
@@ -411,6 +712,12 @@ func (recv *pyroscopeReceiver) Shutdown(ctx context.Context) error {
 	if err := recv.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown: %w", err)
 	}
+	if recv.grpcServer != nil {
+		recv.grpcServer.GracefulStop()
+	}
+	if recv.rateLimiterStopCh != nil {
+		close(recv.rateLimiterStopCh)
+	}
 	recv.shutdownWg.Wait()
 	return nil
 }
@@ -428,3 +735,147 @@ func writeResponse(w http.ResponseWriter, contentType string, statusCode int, pa
 		_, _ = w.Write(payload)
 	}
 }
+
+// grpcHandler implements pyroscopeproto.ProfileIngestServer: a single
+// long-lived bidirectional stream where the client pushes ProfileBatch
+// frames and the server acks each asynchronously on a bounded worker pool,
+// without ever closing the stream.
+type grpcHandler struct {
+	recv *pyroscopeReceiver
+	sem  chan struct{}
+}
+
+func newGrpcHandler(recv *pyroscopeReceiver, maxConcurrentBatches int) *grpcHandler {
+	return &grpcHandler{recv: recv, sem: make(chan struct{}, maxConcurrentBatches)}
+}
+
+func (h *grpcHandler) Send(stream pyroscopeproto.ProfileIngest_SendServer) error {
+	ctx := stream.Context()
+
+	// gRPC forbids concurrent SendMsg calls on one stream from multiple
+	// goroutines, so every worker's ack is funneled through this single
+	// writer goroutine instead of calling stream.Send directly.
+	statusCh := make(chan *pyroscopeproto.BatchStatus)
+	writeDone := make(chan error, 1)
+	go func() {
+		var sendErr error
+		for status := range statusCh {
+			if sendErr != nil {
+				continue // drain to avoid blocking workers after a failed send
+			}
+			if err := stream.Send(status); err != nil {
+				sendErr = err
+			}
+		}
+		writeDone <- sendErr
+	}()
+
+	var wg sync.WaitGroup
+	var recvErr error
+	for {
+		batch, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			recvErr = err
+			break
+		}
+
+		h.sem <- struct{}{}
+		wg.Add(1)
+		go func(batch *pyroscopeproto.ProfileBatch) {
+			defer wg.Done()
+			defer func() { <-h.sem }()
+			status := h.processBatch(ctx, batch)
+			select {
+			case statusCh <- status:
+			case <-ctx.Done():
+			}
+		}(batch)
+	}
+
+	wg.Wait()
+	close(statusCh)
+	writeErr := <-writeDone
+
+	if recvErr != nil {
+		return recvErr
+	}
+	return writeErr
+}
+
+// processBatch decompresses and parses every payload in batch using the same
+// parser interface as the http path, then hands the resulting logs to the
+// next consumer in the pipeline.
+func (h *grpcHandler) processBatch(ctx context.Context, batch *pyroscopeproto.ProfileBatch) *pyroscopeproto.BatchStatus {
+	var pa parser
+	switch batch.Format {
+	case formatJfr:
+		pa = jfrparser.NewJfrPprofParser()
+	case formatPprof:
+		pa = pprofparser.NewPprofParser()
+	default:
+		return errorBatchStatus(batch.BatchId, pyroscopeproto.BatchStatus_PARSE_ERROR, fmt.Sprintf("unsupported format: %s", batch.Format))
+	}
+
+	if batch.Header == nil {
+		return errorBatchStatus(batch.BatchId, pyroscopeproto.BatchStatus_PARSE_ERROR, "batch is missing a header")
+	}
+
+	logs := plog.NewLogs()
+	rs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	md := profile_types.Metadata{SampleRateHertz: batch.Header.SampleRateHertz}
+
+	for _, payload := range batch.Payloads {
+		status := func() *pyroscopeproto.BatchStatus {
+			// buf may back pr.Payload.Bytes() below for parsers that alias
+			// their input, but FromRaw copies those bytes into each log
+			// record, so buf goes back to the shared pool as soon as this
+			// payload's records have been built.
+			buf := acquireBuf(h.recv.uncompressedBufPool)
+			defer releaseBuf(h.recv.uncompressedBufPool, buf)
+
+			if err := h.recv.decompressor.Decompress(bytes.NewReader(payload), batch.ContentEncoding, buf); err != nil {
+				return errorBatchStatus(batch.BatchId, pyroscopeproto.BatchStatus_PARSE_ERROR, fmt.Sprintf("failed to decompress payload: %s", err.Error()))
+			}
+
+			ps, err := pa.Parse(buf, md)
+			if err != nil {
+				return errorBatchStatus(batch.BatchId, pyroscopeproto.BatchStatus_PARSE_ERROR, fmt.Sprintf("failed to parse %s: %s", batch.Format, err.Error()))
+			}
+
+			for _, pr := range ps {
+				r := rs.AppendEmpty()
+				r.SetTimestamp(pcommon.Timestamp(ns(batch.Header.StartUnixMilli / 1e3)))
+				m := r.Attributes()
+				m.PutStr("duration_ns", fmt.Sprint(ns((batch.Header.EndUnixMilli-batch.Header.StartUnixMilli)/1e3)))
+				m.PutStr("service_name", batch.Header.ServiceName)
+				tm := m.PutEmptyMap("tags")
+				for k, v := range batch.Header.Labels {
+					tm.PutStr(k, v)
+				}
+				if err := setAttrsFromProfile(pr, m); err != nil {
+					return errorBatchStatus(batch.BatchId, pyroscopeproto.BatchStatus_PARSE_ERROR, err.Error())
+				}
+				r.Body().SetEmptyBytes().FromRaw(pr.Payload.Bytes())
+			}
+			return nil
+		}()
+		if status != nil {
+			return status
+		}
+	}
+
+	// TODO: support memorylimiter processor, map throttling to OUT_OF_MEMORY
+	// with a populated RetryInfo, depends on
+	// https://github.com/open-telemetry/opentelemetry-collector/issues/9196
+	if err := h.recv.next.ConsumeLogs(ctx, logs); err != nil {
+		return errorBatchStatus(batch.BatchId, pyroscopeproto.BatchStatus_UNAVAILABLE, err.Error())
+	}
+	return &pyroscopeproto.BatchStatus{BatchId: batch.BatchId, Code: pyroscopeproto.BatchStatus_SUCCESS}
+}
+
+func errorBatchStatus(batchId string, code pyroscopeproto.BatchStatus_Code, msg string) *pyroscopeproto.BatchStatus {
+	return &pyroscopeproto.BatchStatus{BatchId: batchId, Code: code, Message: msg}
+}
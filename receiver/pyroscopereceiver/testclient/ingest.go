@@ -52,3 +52,81 @@ func Ingest(addr string, urlParams map[string]string, jfr string) error {
 	}
 	return nil
 }
+
+// IngestPprof uploads a raw pprof-encoded profile using Pyroscope's
+// pprof+sample_type_config multipart form. sampleTypeConfig and
+// previousProfile are optional file paths and may be left empty.
+func IngestPprof(addr string, urlParams map[string]string, pprof string, sampleTypeConfig string, previousProfile string) error {
+	data, err := os.ReadFile(pprof)
+	if err != nil {
+		return err
+	}
+
+	body := new(bytes.Buffer)
+	mw := multipart.NewWriter(body)
+
+	part, err := mw.CreateFormFile("profile", "profile")
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	gw := gzip.NewWriter(part)
+	if _, err := gw.Write(data); err != nil {
+		return err
+	}
+	gw.Close()
+
+	if sampleTypeConfig != "" {
+		cfg, err := os.ReadFile(sampleTypeConfig)
+		if err != nil {
+			return err
+		}
+		cfgPart, err := mw.CreateFormFile("sample_type_config.json", "sample_type_config.json")
+		if err != nil {
+			return fmt.Errorf("failed to create form file: %w", err)
+		}
+		if _, err := cfgPart.Write(cfg); err != nil {
+			return err
+		}
+	}
+
+	if previousProfile != "" {
+		prev, err := os.ReadFile(previousProfile)
+		if err != nil {
+			return err
+		}
+		prevPart, err := mw.CreateFormFile("previous_profile", "previous_profile")
+		if err != nil {
+			return fmt.Errorf("failed to create form file: %w", err)
+		}
+		pgw := gzip.NewWriter(prevPart)
+		if _, err := pgw.Write(prev); err != nil {
+			return err
+		}
+		pgw.Close()
+	}
+	mw.Close()
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/ingest", addr), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", mw.FormDataContentType())
+
+	q := req.URL.Query()
+	q.Add("format", "pprof")
+	for k, v := range urlParams {
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to upload profile; http status code: %d", resp.StatusCode)
+	}
+	return nil
+}